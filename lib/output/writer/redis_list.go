@@ -3,14 +3,15 @@ package writer
 import (
 	"context"
 	"fmt"
-	"net/url"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/Jeffail/benthos/v3/lib/log"
 	"github.com/Jeffail/benthos/v3/lib/metrics"
 	"github.com/Jeffail/benthos/v3/lib/types"
+	bredis "github.com/Jeffail/benthos/v3/lib/util/redis"
+	"github.com/Jeffail/benthos/v3/lib/util/text"
+	btls "github.com/Jeffail/benthos/v3/lib/util/tls"
 	"github.com/go-redis/redis/v7"
 )
 
@@ -18,16 +19,38 @@ import (
 
 // RedisListConfig contains configuration fields for the RedisList output type.
 type RedisListConfig struct {
-	URL         string `json:"url" yaml:"url"`
-	Key         string `json:"key" yaml:"key"`
-	MaxInFlight int    `json:"max_in_flight" yaml:"max_in_flight"`
+	URL        string      `json:"url" yaml:"url"`
+	Kind       string      `json:"kind" yaml:"kind"`
+	MasterName string      `json:"master_name" yaml:"master_name"`
+	Addresses  []string    `json:"addresses" yaml:"addresses"`
+	Username   string      `json:"username" yaml:"username"`
+	Password   string      `json:"password" yaml:"password"`
+	TLS        btls.Config `json:"tls" yaml:"tls"`
+	Key        string      `json:"key" yaml:"key"`
+	// Command is either rpush or lpush, selecting which end of the list
+	// each message part is pushed onto.
+	Command string `json:"command" yaml:"command"`
+	// MaxLength, when greater than zero, LTRIMs the list back down to this
+	// many elements after each pipelined push.
+	MaxLength int64 `json:"max_length" yaml:"max_length"`
+	// MaxInFlight controls how many pipelined chunks of a message batch are
+	// written to Redis concurrently. See Write for the ordering trade-off.
+	MaxInFlight int `json:"max_in_flight" yaml:"max_in_flight"`
 }
 
 // NewRedisListConfig creates a new RedisListConfig with default values.
 func NewRedisListConfig() RedisListConfig {
 	return RedisListConfig{
 		URL:         "tcp://localhost:6379",
+		Kind:        "standard",
+		MasterName:  "",
+		Addresses:   []string{},
+		Username:    "",
+		Password:    "",
+		TLS:         btls.NewConfig(),
 		Key:         "benthos_list",
+		Command:     "rpush",
+		MaxLength:   0,
 		MaxInFlight: 1,
 	}
 }
@@ -39,13 +62,30 @@ type RedisList struct {
 	log   log.Modular
 	stats metrics.Type
 
-	url  *url.URL
-	conf RedisListConfig
+	conf     RedisListConfig
+	poolConf bredis.Config
 
-	client  *redis.Client
+	key        []byte
+	keyDynamic bool
+
+	client  redis.UniversalClient
 	connMut sync.RWMutex
 }
 
+// poolConfig builds the shared bredis.Config used to obtain a client from
+// the Redis client pool.
+func (c RedisListConfig) poolConfig() bredis.Config {
+	return bredis.Config{
+		URL:        c.URL,
+		Kind:       c.Kind,
+		MasterName: c.MasterName,
+		Addresses:  c.Addresses,
+		Username:   c.Username,
+		Password:   c.Password,
+		TLS:        c.TLS,
+	}
+}
+
 // NewRedisList creates a new RedisList output type.
 func NewRedisList(
 	conf RedisListConfig,
@@ -53,21 +93,49 @@ func NewRedisList(
 	stats metrics.Type,
 ) (*RedisList, error) {
 
+	key := []byte(conf.Key)
 	r := &RedisList{
-		log:   log,
-		stats: stats,
-		conf:  conf,
+		log:        log,
+		stats:      stats,
+		conf:       conf,
+		poolConf:   conf.poolConfig(),
+		key:        key,
+		keyDynamic: text.ContainsFunctionVariables(key),
 	}
 
-	var err error
-	r.url, err = url.Parse(conf.URL)
-	if err != nil {
-		return nil, err
+	switch conf.Kind {
+	case "standard", "failover", "cluster":
+	default:
+		return nil, fmt.Errorf("invalid redis kind '%v', must be standard, failover or cluster", conf.Kind)
+	}
+
+	if conf.Kind == "failover" && conf.MasterName == "" {
+		return nil, fmt.Errorf("a master_name must be specified when kind is failover")
+	}
+
+	if (conf.Kind == "failover" || conf.Kind == "cluster") && len(conf.Addresses) == 0 {
+		return nil, fmt.Errorf("at least one address must be specified when kind is %v", conf.Kind)
+	}
+
+	switch conf.Command {
+	case "rpush", "lpush":
+	default:
+		return nil, fmt.Errorf("invalid command '%v', must be rpush or lpush", conf.Command)
 	}
 
 	return r, nil
 }
 
+// keyFor resolves the Redis list key that a given message part should be
+// pushed to, evaluating any function variables against the part if the
+// configured key is dynamic.
+func (r *RedisList) keyFor(msg types.Message, part int) string {
+	if !r.keyDynamic {
+		return r.conf.Key
+	}
+	return string(text.ReplaceFunctionVariables(msg, part, r.key))
+}
+
 //------------------------------------------------------------------------------
 
 // ConnectWithContext establishes a connection to an RedisList server.
@@ -80,35 +148,16 @@ func (r *RedisList) Connect() error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 
-	var pass string
-	if r.url.User != nil {
-		pass, _ = r.url.User.Password()
-	}
-
-	// We default to Redis DB 0 for backward compatibilitiy, but if it's
-	// specified in the URL, we'll use the specified one instead.
-	var redisDB int
-	if len(r.url.Path) > 1 {
-		var err error
-		// We'll strip the leading '/'
-		redisDB, err = strconv.Atoi(r.url.Path[1:])
-		if err != nil {
-			return fmt.Errorf("invalid Redis DB, can't parse '%s'", r.url.Path)
-		}
-	}
-
-	client := redis.NewClient(&redis.Options{
-		Addr:     r.url.Host,
-		Network:  r.url.Scheme,
-		DB:       redisDB,
-		Password: pass,
-	})
-
-	if _, err := client.Ping().Result(); err != nil {
+	client, err := bredis.GetClient(r.poolConf)
+	if err != nil {
 		return err
 	}
 
-	r.log.Infof("Pushing messages to Redis list: %v\n", r.conf.Key)
+	if r.keyDynamic {
+		r.log.Infof("Pushing messages to dynamic Redis lists matching: %v\n", r.conf.Key)
+	} else {
+		r.log.Infof("Pushing messages to Redis list: %v\n", r.conf.Key)
+	}
 
 	r.client = client
 	return nil
@@ -122,7 +171,14 @@ func (r *RedisList) WriteWithContext(ctx context.Context, msg types.Message) err
 	return r.Write(msg)
 }
 
-// Write attempts to write a message by pushing it to the end of a Redis list.
+// Write attempts to write a message by pushing its parts to Redis list(s) in
+// pipelined chunks, split across MaxInFlight concurrent goroutines. The
+// baseline MaxInFlight of 1 preserves the strict per-message ordering of a
+// single RPush/LPush per part; raising it trades that ordering guarantee for
+// throughput, since chunks are no longer written in any guaranteed order
+// relative to each other, and, when MaxLength is set, two chunks that
+// resolve to the same key race their LTRIMs against each other on the
+// server (see writeChunk).
 func (r *RedisList) Write(msg types.Message) error {
 	r.connMut.RLock()
 	client := r.client
@@ -132,22 +188,95 @@ func (r *RedisList) Write(msg types.Message) error {
 		return types.ErrNotConnected
 	}
 
-	return msg.Iter(func(i int, p types.Part) error {
-		if err := client.RPush(r.conf.Key, p.Get()).Err(); err != nil {
+	parts := make([]int, msg.Len())
+	for i := range parts {
+		parts[i] = i
+	}
+
+	nWorkers := r.conf.MaxInFlight
+	if nWorkers < 1 {
+		nWorkers = 1
+	}
+	if nWorkers > len(parts) && len(parts) > 0 {
+		nWorkers = len(parts)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, nWorkers)
+	for i, chunk := range splitParts(parts, nWorkers) {
+		wg.Add(1)
+		go func(i int, chunk []int) {
+			defer wg.Done()
+			errs[i] = r.writeChunk(client, msg, chunk)
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
 			r.disconnect()
 			r.log.Errorf("Error from redis: %v\n", err)
 			return types.ErrNotConnected
 		}
+	}
+	return nil
+}
+
+// writeChunk pipelines a push of each message part in chunk onto its
+// resolved list key, trimming each touched list afterwards when a
+// max_length is set. Each concurrent call from Write runs its own pipeline,
+// so when MaxInFlight > 1 and two chunks resolve to the same key, their
+// pushes and trims are not ordered with respect to each other.
+func (r *RedisList) writeChunk(client redis.UniversalClient, msg types.Message, chunk []int) error {
+	if len(chunk) == 0 {
 		return nil
-	})
+	}
+
+	pipe := client.Pipeline()
+	touchedKeys := map[string]struct{}{}
+	for _, index := range chunk {
+		key := r.keyFor(msg, index)
+		data := msg.Get(index).Get()
+		if r.conf.Command == "lpush" {
+			pipe.LPush(key, data)
+		} else {
+			pipe.RPush(key, data)
+		}
+		touchedKeys[key] = struct{}{}
+	}
+	if r.conf.MaxLength > 0 {
+		for key := range touchedKeys {
+			if r.conf.Command == "lpush" {
+				pipe.LTrim(key, 0, r.conf.MaxLength-1)
+			} else {
+				pipe.LTrim(key, -r.conf.MaxLength, -1)
+			}
+		}
+	}
+
+	_, err := pipe.Exec()
+	return err
+}
+
+// splitParts divides part indexes into n roughly even, contiguous chunks.
+func splitParts(parts []int, n int) [][]int {
+	if n < 1 {
+		n = 1
+	}
+	chunks := make([][]int, n)
+	for i, part := range parts {
+		idx := i * n / len(parts)
+		chunks[idx] = append(chunks[idx], part)
+	}
+	return chunks
 }
 
-// disconnect safely closes a connection to an RedisList server.
+// disconnect safely releases the RedisList's reference to its shared client.
 func (r *RedisList) disconnect() error {
 	r.connMut.Lock()
 	defer r.connMut.Unlock()
 	if r.client != nil {
-		err := r.client.Close()
+		err := bredis.ReleaseClient(r.poolConf)
 		r.client = nil
 		return err
 	}
@@ -0,0 +1,152 @@
+package writer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/log"
+	"github.com/Jeffail/benthos/v3/lib/message"
+	"github.com/Jeffail/benthos/v3/lib/metrics"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v7"
+)
+
+func TestSplitParts(t *testing.T) {
+	tests := []struct {
+		name   string
+		parts  []int
+		n      int
+		expect [][]int
+	}{
+		{
+			name:   "even division",
+			parts:  []int{0, 1, 2, 3},
+			n:      2,
+			expect: [][]int{{0, 1}, {2, 3}},
+		},
+		{
+			name:   "uneven division",
+			parts:  []int{0, 1, 2, 3, 4},
+			n:      2,
+			expect: [][]int{{0, 1, 2}, {3, 4}},
+		},
+		{
+			name:   "single worker keeps one contiguous chunk",
+			parts:  []int{0, 1, 2},
+			n:      1,
+			expect: [][]int{{0, 1, 2}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := splitParts(test.parts, test.n)
+			if !reflect.DeepEqual(got, test.expect) {
+				t.Errorf("splitParts(%v, %v) = %v, want %v", test.parts, test.n, got, test.expect)
+			}
+		})
+	}
+}
+
+func newTestRedisList(t *testing.T, mr *miniredis.Miniredis, conf RedisListConfig) (*RedisList, redis.UniversalClient) {
+	t.Helper()
+
+	r, err := NewRedisList(conf, log.Noop(), metrics.Noop())
+	if err != nil {
+		t.Fatalf("NewRedisList: %v", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	r.client = client
+	return r, client
+}
+
+func TestWriteChunkLTrimBoundsRPush(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	conf := NewRedisListConfig()
+	conf.Key = "mylist"
+	conf.Command = "rpush"
+	conf.MaxLength = 2
+
+	r, client := newTestRedisList(t, mr, conf)
+
+	msg := message.New([][]byte{[]byte("a"), []byte("b"), []byte("c")})
+	if err := r.writeChunk(client, msg, []int{0, 1, 2}); err != nil {
+		t.Fatalf("writeChunk: %v", err)
+	}
+
+	got, err := mr.List("mylist")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if want := []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rpush+ltrim list = %v, want %v (should keep the last MaxLength elements)", got, want)
+	}
+}
+
+func TestWriteChunkLTrimBoundsLPush(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	conf := NewRedisListConfig()
+	conf.Key = "mylist"
+	conf.Command = "lpush"
+	conf.MaxLength = 2
+
+	r, client := newTestRedisList(t, mr, conf)
+
+	msg := message.New([][]byte{[]byte("a"), []byte("b"), []byte("c")})
+	if err := r.writeChunk(client, msg, []int{0, 1, 2}); err != nil {
+		t.Fatalf("writeChunk: %v", err)
+	}
+
+	got, err := mr.List("mylist")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if want := []string{"c", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("lpush+ltrim list = %v, want %v (should keep the first MaxLength elements)", got, want)
+	}
+}
+
+func TestKeyForDynamicPerPart(t *testing.T) {
+	conf := NewRedisListConfig()
+	conf.Key = "benthos_list_${!metadata:tenant}"
+
+	r, err := NewRedisList(conf, log.Noop(), metrics.Noop())
+	if err != nil {
+		t.Fatalf("NewRedisList: %v", err)
+	}
+	if !r.keyDynamic {
+		t.Fatalf("expected a key containing a function variable to be detected as dynamic")
+	}
+
+	msg := message.New([][]byte{[]byte("foo"), []byte("bar")})
+	msg.Get(0).Metadata().Set("tenant", "acme")
+	msg.Get(1).Metadata().Set("tenant", "globex")
+
+	if got, want := r.keyFor(msg, 0), "benthos_list_acme"; got != want {
+		t.Errorf("keyFor(msg, 0) = %q, want %q", got, want)
+	}
+	if got, want := r.keyFor(msg, 1), "benthos_list_globex"; got != want {
+		t.Errorf("keyFor(msg, 1) = %q, want %q", got, want)
+	}
+}
+
+func TestKeyForStatic(t *testing.T) {
+	conf := NewRedisListConfig()
+	conf.Key = "benthos_list"
+
+	r, err := NewRedisList(conf, log.Noop(), metrics.Noop())
+	if err != nil {
+		t.Fatalf("NewRedisList: %v", err)
+	}
+	if r.keyDynamic {
+		t.Fatalf("expected a plain key to be detected as static")
+	}
+
+	msg := message.New([][]byte{[]byte("foo")})
+	if got, want := r.keyFor(msg, 0), "benthos_list"; got != want {
+		t.Errorf("keyFor(msg, 0) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,241 @@
+// Package redis provides a pool of shared Redis clients so that components
+// within a single Benthos config (inputs, outputs, caches, rate limits) that
+// point at the same Redis deployment can re-use a single connection rather
+// than each opening their own.
+package redis
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	btls "github.com/Jeffail/benthos/v3/lib/util/tls"
+	"github.com/go-redis/redis/v7"
+)
+
+//------------------------------------------------------------------------------
+
+// Config describes how to reach a Redis deployment. Redis based components
+// build one from their own config fields so that they can be resolved to a
+// client via GetClient.
+type Config struct {
+	URL        string      `json:"url" yaml:"url"`
+	Kind       string      `json:"kind" yaml:"kind"`
+	MasterName string      `json:"master_name" yaml:"master_name"`
+	Addresses  []string    `json:"addresses" yaml:"addresses"`
+	Username   string      `json:"username" yaml:"username"`
+	Password   string      `json:"password" yaml:"password"`
+	TLS        btls.Config `json:"tls" yaml:"tls"`
+}
+
+// NewConfig creates a new Config with default values.
+func NewConfig() Config {
+	return Config{
+		URL:        "tcp://localhost:6379",
+		Kind:       "standard",
+		MasterName: "",
+		Addresses:  []string{},
+		Username:   "",
+		Password:   "",
+		TLS:        btls.NewConfig(),
+	}
+}
+
+// key returns a canonical string identifying the deployment a Config points
+// at, used to de-duplicate clients within the pool. Two configs must only
+// ever share a client if every field that affects how the connection is
+// authenticated or encrypted is identical, so secrets (Password) and the
+// TLS config are folded in as a hash rather than compared as plain text.
+func (c Config) key() string {
+	addrs := append([]string(nil), c.Addresses...)
+	sort.Strings(addrs)
+
+	secrets, _ := json.Marshal(struct {
+		Password string
+		TLS      btls.Config
+	}{c.Password, c.TLS})
+	secretsHash := sha256.Sum256(secrets)
+
+	return strings.Join([]string{
+		c.URL, c.Kind, c.MasterName, strings.Join(addrs, ","), c.Username,
+		fmt.Sprintf("%x", secretsHash),
+	}, "|")
+}
+
+//------------------------------------------------------------------------------
+
+type pooledClient struct {
+	client   redis.UniversalClient
+	refCount int
+}
+
+var (
+	poolMut sync.Mutex
+	pool    = map[string]*pooledClient{}
+)
+
+// GetClient returns a connected redis.UniversalClient for the given Config.
+// The first caller for a given deployment dials and connects the client;
+// subsequent callers for the same deployment are handed the existing client,
+// re-pinged to confirm it's still alive, with its reference count
+// incremented. A pooled client that fails that liveness check is evicted and
+// replaced rather than handed out dead. Dialing and pinging both happen
+// outside of poolMut, which is only held to check-and-insert, so a slow or
+// unreachable deployment never stalls GetClient/ReleaseClient calls for
+// other deployments. Each successful call must be paired with a call to
+// ReleaseClient once the client is no longer needed.
+func GetClient(conf Config) (redis.UniversalClient, error) {
+	key := conf.key()
+
+	poolMut.Lock()
+	entry, exists := pool[key]
+	if exists {
+		entry.refCount++
+	}
+	poolMut.Unlock()
+
+	if exists {
+		if _, err := entry.client.Ping().Result(); err == nil {
+			return entry.client, nil
+		}
+		releaseEntry(key, entry)
+	}
+
+	client, err := newClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = client.Ping().Result(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	poolMut.Lock()
+	defer poolMut.Unlock()
+
+	// Another caller may have raced us to create this key; prefer whichever
+	// entry is already registered and drop the loser.
+	if existing, raced := pool[key]; raced {
+		existing.refCount++
+		client.Close()
+		return existing.client, nil
+	}
+
+	pool[key] = &pooledClient{client: client, refCount: 1}
+	return client, nil
+}
+
+// releaseEntry drops our speculative reference to a pooled entry that failed
+// its liveness check, evicting and closing it once no references remain.
+func releaseEntry(key string, entry *pooledClient) {
+	poolMut.Lock()
+	defer poolMut.Unlock()
+
+	entry.refCount--
+	if entry.refCount > 0 {
+		return
+	}
+	if pool[key] == entry {
+		delete(pool, key)
+	}
+	entry.client.Close()
+}
+
+// ReleaseClient decrements the reference count of the shared client
+// associated with conf, closing and evicting it from the pool once the last
+// reference is released.
+func ReleaseClient(conf Config) error {
+	poolMut.Lock()
+	defer poolMut.Unlock()
+
+	key := conf.key()
+	entry, exists := pool[key]
+	if !exists {
+		return nil
+	}
+
+	entry.refCount--
+	if entry.refCount > 0 {
+		return nil
+	}
+
+	delete(pool, key)
+	return entry.client.Close()
+}
+
+//------------------------------------------------------------------------------
+
+func newClient(conf Config) (redis.UniversalClient, error) {
+	u, err := url.Parse(conf.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	pass := conf.Password
+	if pass == "" && u.User != nil {
+		pass, _ = u.User.Password()
+	}
+
+	// We default to Redis DB 0 for backward compatibilitiy, but if it's
+	// specified in the URL, we'll use the specified one instead.
+	var redisDB int
+	if len(u.Path) > 1 {
+		// We'll strip the leading '/'
+		if redisDB, err = strconv.Atoi(u.Path[1:]); err != nil {
+			return nil, fmt.Errorf("invalid Redis DB, can't parse '%s'", u.Path)
+		}
+	}
+
+	var tlsConf *tls.Config
+	if conf.TLS.Enabled {
+		if tlsConf, err = conf.TLS.ToGoTLSConfig(); err != nil {
+			return nil, fmt.Errorf("failed to construct TLS config: %w", err)
+		}
+	}
+
+	switch conf.Kind {
+	case "failover":
+		if conf.MasterName == "" {
+			return nil, fmt.Errorf("a master_name must be specified when kind is failover")
+		}
+		if len(conf.Addresses) == 0 {
+			return nil, fmt.Errorf("at least one address must be specified when kind is failover")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    conf.MasterName,
+			SentinelAddrs: conf.Addresses,
+			DB:            redisDB,
+			Username:      conf.Username,
+			Password:      pass,
+			TLSConfig:     tlsConf,
+		}), nil
+	case "cluster":
+		if len(conf.Addresses) == 0 {
+			return nil, fmt.Errorf("at least one address must be specified when kind is cluster")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     conf.Addresses,
+			Username:  conf.Username,
+			Password:  pass,
+			TLSConfig: tlsConf,
+		}), nil
+	case "standard":
+		return redis.NewClient(&redis.Options{
+			Addr:      u.Host,
+			Network:   u.Scheme,
+			DB:        redisDB,
+			Username:  conf.Username,
+			Password:  pass,
+			TLSConfig: tlsConf,
+		}), nil
+	}
+	return nil, fmt.Errorf("invalid redis kind '%v', must be standard, failover or cluster", conf.Kind)
+}
+
+//------------------------------------------------------------------------------
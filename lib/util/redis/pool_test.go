@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v7"
+)
+
+func TestConfigKeyIncludesSecrets(t *testing.T) {
+	a := NewConfig()
+	b := NewConfig()
+	b.Password = "different"
+
+	if a.key() == b.key() {
+		t.Fatalf("configs with different passwords must not share a pool key")
+	}
+
+	c := NewConfig()
+	c.TLS.Enabled = true
+
+	if a.key() == c.key() {
+		t.Fatalf("configs with different TLS settings must not share a pool key")
+	}
+}
+
+func TestGetClientReleaseClientRefCounting(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	conf := NewConfig()
+	conf.URL = "tcp://" + mr.Addr()
+	key := conf.key()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	poolMut.Lock()
+	pool[key] = &pooledClient{client: client, refCount: 1}
+	poolMut.Unlock()
+	defer func() {
+		poolMut.Lock()
+		delete(pool, key)
+		poolMut.Unlock()
+	}()
+
+	got, err := GetClient(conf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != client {
+		t.Fatalf("expected the existing, live pooled client to be returned")
+	}
+
+	poolMut.Lock()
+	if pool[key].refCount != 2 {
+		t.Fatalf("expected refCount 2 after a second GetClient, got %d", pool[key].refCount)
+	}
+	poolMut.Unlock()
+
+	if err := ReleaseClient(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	poolMut.Lock()
+	entry, exists := pool[key]
+	if !exists {
+		t.Fatalf("expected entry to remain after releasing one of two references")
+	}
+	if entry.refCount != 1 {
+		t.Fatalf("expected refCount 1, got %d", entry.refCount)
+	}
+	poolMut.Unlock()
+
+	if err := ReleaseClient(conf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	poolMut.Lock()
+	if _, exists := pool[key]; exists {
+		t.Fatalf("expected entry to be evicted after the last reference is released")
+	}
+	poolMut.Unlock()
+}
+
+// TestGetClientReplacesDeadEntry confirms that a pooled client whose
+// deployment has gone away is re-pinged and evicted rather than handed out
+// forever, which was the regression the liveness check was added to fix.
+func TestGetClientReplacesDeadEntry(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	conf := NewConfig()
+	conf.URL = "tcp://" + server.Addr()
+	key := conf.key()
+
+	deadClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	poolMut.Lock()
+	pool[key] = &pooledClient{client: deadClient, refCount: 1}
+	poolMut.Unlock()
+	defer func() {
+		poolMut.Lock()
+		delete(pool, key)
+		poolMut.Unlock()
+	}()
+
+	server.Close()
+
+	if _, err := GetClient(conf); err == nil {
+		t.Fatalf("expected GetClient to surface a dial failure once the dead entry is evicted, got nil error")
+	}
+
+	poolMut.Lock()
+	_, exists := pool[key]
+	poolMut.Unlock()
+	if exists {
+		t.Fatalf("expected the dead entry to have been evicted from the pool")
+	}
+}
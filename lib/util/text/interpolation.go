@@ -0,0 +1,101 @@
+// Package text provides helpers for interpolating per-message function
+// variables into plain strings, as used throughout Benthos component
+// configs that accept dynamic fields. Both the colon-arg form
+// (${!function:argument}) and the call-style form
+// (${!function("argument")} or ${!function('argument')}) are recognised.
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/Jeffail/benthos/v3/lib/types"
+)
+
+//------------------------------------------------------------------------------
+
+var functionRegex = regexp.MustCompile(`\$\{!([a-zA-Z_]+)(?:\(\s*"([^"]*)"\s*\)|\(\s*'([^']*)'\s*\)|:([^}]*))?\}`)
+
+// ContainsFunctionVariables returns true if a byte slice contains function
+// variable replace patterns, e.g. ${!metadata:foo} or ${!json("foo")}.
+func ContainsFunctionVariables(b []byte) bool {
+	return functionRegex.Match(b)
+}
+
+// ReplaceFunctionVariables searches for occurrences of ${!function:argument}
+// or ${!function("argument")} within a byte slice and replaces them with
+// values obtained from the given message part. Unrecognised functions are
+// left in place.
+func ReplaceFunctionVariables(msg types.Message, part int, b []byte) []byte {
+	return functionRegex.ReplaceAllFunc(b, func(match []byte) []byte {
+		groups := functionRegex.FindSubmatch(match)
+		name := string(groups[1])
+
+		var arg string
+		switch {
+		case groups[2] != nil:
+			arg = string(groups[2])
+		case groups[3] != nil:
+			arg = string(groups[3])
+		case groups[4] != nil:
+			arg = string(groups[4])
+		}
+
+		value, ok := resolveFunction(msg, part, name, arg)
+		if !ok {
+			return match
+		}
+		return []byte(value)
+	})
+}
+
+func resolveFunction(msg types.Message, part int, name, arg string) (string, bool) {
+	switch name {
+	case "metadata":
+		if part < 0 || part >= msg.Len() {
+			return "", false
+		}
+		return msg.Get(part).Metadata().Get(arg), true
+	case "json", "json_field":
+		if part < 0 || part >= msg.Len() {
+			return "", false
+		}
+		return jsonField(msg.Get(part).Get(), arg)
+	}
+	return "", false
+}
+
+func jsonField(data []byte, path string) (string, bool) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return "", false
+	}
+
+	current := root
+	if path != "" {
+		for _, field := range strings.Split(path, ".") {
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			if current, ok = obj[field]; !ok {
+				return "", false
+			}
+		}
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v, true
+	default:
+		out, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(bytes.Trim(out, `"`)), true
+	}
+}
+
+//------------------------------------------------------------------------------
@@ -0,0 +1,44 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/Jeffail/benthos/v3/lib/message"
+)
+
+func TestContainsFunctionVariables(t *testing.T) {
+	tests := map[string]bool{
+		"benthos_list":                          false,
+		"benthos_list_${!metadata:tenant}":      true,
+		`benthos_list_${!json("tenant")}`:       true,
+		`benthos_list_${!json('tenant')}`:       true,
+		"benthos_list_${!json_field:tenant.id}": true,
+	}
+	for input, expected := range tests {
+		if got := ContainsFunctionVariables([]byte(input)); got != expected {
+			t.Errorf("ContainsFunctionVariables(%q) = %v, want %v", input, got, expected)
+		}
+	}
+}
+
+func TestReplaceFunctionVariables(t *testing.T) {
+	msg := message.New(nil)
+	part := message.NewPart([]byte(`{"tenant":{"id":"foo"}}`))
+	part.Metadata().Set("queue_name", "bar")
+	msg.Append(part)
+
+	tests := map[string]string{
+		"benthos_list":                            "benthos_list",
+		"benthos_list_${!metadata:queue_name}":    "benthos_list_bar",
+		`benthos_list_${!json("tenant.id")}`:      "benthos_list_foo",
+		`benthos_list_${!json('tenant.id')}`:      "benthos_list_foo",
+		"benthos_list_${!json_field:tenant.id}":   "benthos_list_foo",
+		"benthos_list_${!unknown_function:thing}": "benthos_list_${!unknown_function:thing}",
+	}
+	for input, expected := range tests {
+		got := string(ReplaceFunctionVariables(msg, 0, []byte(input)))
+		if got != expected {
+			t.Errorf("ReplaceFunctionVariables(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
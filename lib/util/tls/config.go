@@ -0,0 +1,95 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+//------------------------------------------------------------------------------
+
+// ClientCertificate represents a single client certificate for an engine to
+// use for authentication either from a file or directly supplied.
+type ClientCertificate struct {
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+	Cert     string `json:"cert" yaml:"cert"`
+	Key      string `json:"key" yaml:"key"`
+}
+
+// Config contains configuration params for TLS.
+type Config struct {
+	Enabled            bool                `json:"enabled" yaml:"enabled"`
+	SkipCertVerify     bool                `json:"skip_cert_verify" yaml:"skip_cert_verify"`
+	RootCAsFile        string              `json:"root_cas_file" yaml:"root_cas_file"`
+	RootCAs            string              `json:"root_cas" yaml:"root_cas"`
+	ClientCertificates []ClientCertificate `json:"client_certs" yaml:"client_certs"`
+}
+
+// NewConfig creates a new Config with default values.
+func NewConfig() Config {
+	return Config{
+		Enabled:            false,
+		SkipCertVerify:     false,
+		RootCAsFile:        "",
+		RootCAs:            "",
+		ClientCertificates: []ClientCertificate{},
+	}
+}
+
+//------------------------------------------------------------------------------
+
+// ToGoTLSConfig attempts to create a valid *tls.Config from the fields of
+// the Config.
+func (c *Config) ToGoTLSConfig() (*tls.Config, error) {
+	var rootCAs *x509.CertPool
+
+	if len(c.RootCAsFile) > 0 || len(c.RootCAs) > 0 {
+		rootCAs = x509.NewCertPool()
+
+		caCert := []byte(c.RootCAs)
+		if len(c.RootCAsFile) > 0 {
+			var err error
+			if caCert, err = ioutil.ReadFile(c.RootCAsFile); err != nil {
+				return nil, err
+			}
+		}
+
+		if !rootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse root cert PEM")
+		}
+	}
+
+	config := &tls.Config{
+		InsecureSkipVerify: c.SkipCertVerify,
+		RootCAs:            rootCAs,
+	}
+
+	for _, conf := range c.ClientCertificates {
+		cert, err := conf.Load()
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+
+	return config, nil
+}
+
+// Load returns a parsed tls.Certificate from either the inline or file
+// based fields of a ClientCertificate.
+func (c *ClientCertificate) Load() (tls.Certificate, error) {
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return tls.Certificate{}, fmt.Errorf("both cert_file and key_file must be specified")
+		}
+		return tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	}
+	if c.Cert == "" || c.Key == "" {
+		return tls.Certificate{}, fmt.Errorf("both cert and key must be specified")
+	}
+	return tls.X509KeyPair([]byte(c.Cert), []byte(c.Key))
+}
+
+//------------------------------------------------------------------------------